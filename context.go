@@ -0,0 +1,70 @@
+package go_frodokem
+
+// domainLabel domain-separates the context-bound KDF in EncapsulateWithContext
+// / DecapsulateWithContext from the plain ct || _k KDF used by
+// Encapsulate/Dencapsulate, so the two can never collide even if ctx is empty.
+const domainLabel = "FrodoKEM/ctx/v1"
+
+// Session exposes the intermediate values FrodoKEM would otherwise discard
+// after deriving the shared secret: the encoded message µ (or, on the
+// decapsulating side, its recovered µ') and the KDF input key K (the
+// auxiliary key _k on encapsulation, or its implicit-rejection-selected
+// counterpart k'/s on decapsulation). Higher-level protocols that need more
+// than one sub-key — or that want to bind their own transcript hash — can
+// derive directly from Session instead of paying for a second KDF pass
+// through Encapsulate/Dencapsulate's own SHAKE call.
+type Session struct {
+	Mu []byte
+	K  []byte
+}
+
+// ExtractSession runs encapsulation against pk and returns the ciphertext
+// together with the Session material, without computing FrodoKEM's own
+// shared secret. Use this when a caller needs to derive more than one
+// sub-key from a single encapsulation (e.g. separate keys for each
+// direction of a channel).
+func (k *FrodoKEM) ExtractSession(pk []uint8) (ct []uint8, session *Session, err error) {
+	return k.encapsulateSession(pk)
+}
+
+// ExtractSessionFromCiphertext runs decapsulation against ct and returns the
+// Session material, without computing FrodoKEM's own shared secret. It is
+// the decapsulating-side counterpart to ExtractSession.
+func (k *FrodoKEM) ExtractSessionFromCiphertext(sk []uint8, ct []uint8) (session *Session, err error) {
+	return k.decapsulateSession(sk, ct)
+}
+
+// EncapsulateWithContext behaves like Encapsulate, except the shared secret
+// is derived as SHAKE(domain || ctx || ct || _k) instead of SHAKE(ct || _k),
+// binding ctx (e.g. a transcript hash) into the KDF. This is meant for
+// hybrid handshakes (e.g. X25519+FrodoKEM) where the outer protocol needs
+// every component KEM to commit to the same transcript.
+func (k *FrodoKEM) EncapsulateWithContext(pk []uint8, ctx []byte) (ct []uint8, ssEnc []uint8, err error) {
+	ct, session, err := k.encapsulateSession(pk)
+	if err != nil {
+		return
+	}
+	ssEnc = k.contextKDF(ctx, ct, session.K)
+	return ct, ssEnc, nil
+}
+
+// DecapsulateWithContext is the decapsulating-side counterpart to
+// EncapsulateWithContext: ctx must match the value passed to
+// EncapsulateWithContext for the shared secrets to agree.
+func (k *FrodoKEM) DecapsulateWithContext(sk []uint8, ct []uint8, ctx []byte) (ssDec []uint8, err error) {
+	session, err := k.decapsulateSession(sk, ct)
+	if err != nil {
+		return
+	}
+	ssDec = k.contextKDF(ctx, ct, session.K)
+	return ssDec, nil
+}
+
+// contextKDF computes SHAKE(domain || ctx || ct || key), truncated to
+// lenSS/8 bytes.
+func (k *FrodoKEM) contextKDF(ctx []byte, ct []byte, key []byte) []byte {
+	input := append([]byte(domainLabel), ctx...)
+	input = append(input, ct...)
+	input = append(input, key...)
+	return k.shake(input, k.lenSS/8)
+}
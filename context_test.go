@@ -0,0 +1,67 @@
+package go_frodokem
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestContextBindsSharedSecret checks the two properties
+// EncapsulateWithContext/DecapsulateWithContext exist for: decapsulating
+// with the same ctx that was used to encapsulate agrees on the shared
+// secret, and decapsulating with a different ctx does not.
+func TestContextBindsSharedSecret(t *testing.T) {
+	k := NewFrodoKEM640AES()
+	pk, sk := k.Keygen()
+
+	ctxA := []byte("session-A")
+	ctxB := []byte("session-B")
+
+	ct, ssEnc, err := k.EncapsulateWithContext(pk, ctxA)
+	if err != nil {
+		t.Fatalf("EncapsulateWithContext: %v", err)
+	}
+
+	ssDecA, err := k.DecapsulateWithContext(sk, ct, ctxA)
+	if err != nil {
+		t.Fatalf("DecapsulateWithContext: %v", err)
+	}
+	if !bytes.Equal(ssEnc, ssDecA) {
+		t.Fatal("DecapsulateWithContext with the matching ctx did not agree with EncapsulateWithContext")
+	}
+
+	ssDecB, err := k.DecapsulateWithContext(sk, ct, ctxB)
+	if err != nil {
+		t.Fatalf("DecapsulateWithContext: %v", err)
+	}
+	if bytes.Equal(ssEnc, ssDecB) {
+		t.Fatal("DecapsulateWithContext with a mismatched ctx agreed with EncapsulateWithContext")
+	}
+}
+
+// TestContextKDFVariesWithCtx is TestContextBindsSharedSecret's more direct
+// twin: for one fixed (ct, session) pair, two different ctx values must
+// feed contextKDF to two different outputs, and the same ctx must be
+// deterministic.
+func TestContextKDFVariesWithCtx(t *testing.T) {
+	k := NewFrodoKEM640AES()
+	pk, _ := k.Keygen()
+
+	ct, session, err := k.ExtractSession(pk)
+	if err != nil {
+		t.Fatalf("ExtractSession: %v", err)
+	}
+
+	ctxA := []byte("ctx-A")
+	ctxB := []byte("ctx-B")
+
+	ssA1 := k.contextKDF(ctxA, ct, session.K)
+	ssA2 := k.contextKDF(ctxA, ct, session.K)
+	if !bytes.Equal(ssA1, ssA2) {
+		t.Fatal("contextKDF is not deterministic for the same (ctx, ct, key)")
+	}
+
+	ssB := k.contextKDF(ctxB, ct, session.K)
+	if bytes.Equal(ssA1, ssB) {
+		t.Fatal("contextKDF produced the same output for two different ctx values")
+	}
+}
@@ -0,0 +1,30 @@
+package frodo640aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestRoundTrip checks that the generated Frodo640AES facade still performs
+// a correct key-pair/encapsulate/decapsulate round trip — i.e. that forwarding
+// straight to the dynamic frodokem.FrodoKEM core (see gen.go) hasn't dropped
+// or miswired anything on the way from the typed facade to the []uint8 API.
+func TestRoundTrip(t *testing.T) {
+	v := New()
+
+	pk, sk := v.Keygen()
+
+	ct, ssEnc, err := v.Encapsulate(pk)
+	if err != nil {
+		t.Fatalf("Encapsulate: %v", err)
+	}
+
+	ssDec, err := v.Decapsulate(sk, ct)
+	if err != nil {
+		t.Fatalf("Decapsulate: %v", err)
+	}
+
+	if !bytes.Equal(ssEnc, ssDec) {
+		t.Fatal("Decapsulate did not recover the shared secret Encapsulate produced")
+	}
+}
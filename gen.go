@@ -0,0 +1,122 @@
+//go:build ignore
+
+package main
+
+// gen.go is the template driver behind `go generate` for the per-variant
+// FrodoKEM packages (frodo640aes, frodo640shake, frodo976aes, ...). Run it
+// with:
+//
+//	go run gen.go
+//
+// (the //go:generate directive lives next to newFrodoKEM in variants.go).
+// It emits, for every entry in variantParams below, a
+// <package>/<package>.go file exposing compile-time-sized array types and a
+// typed facade over the dynamic *FrodoKEM core in this package. Edit the
+// template or variantParams, not the generated files.
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// variantParam describes one FrodoKEM variant well enough to generate its
+// package: the constants mirror the struct fields FrodoKEM already carries
+// at runtime (see newFrodoKEM), just promoted to named compile-time
+// constants so the generated package can size its array types without a
+// slice-of-slices allocation per matrix.
+type variantParam struct {
+	Package  string // e.g. "frodo640aes"
+	TypeName string // e.g. "Frodo640AES"
+	Ctor     string // constructor in the parent package, e.g. "NewFrodoKEM640AES"
+	N        int
+	NBar     int
+	MBar     int
+	D        int
+	B        int
+}
+
+var variantParams = []variantParam{
+	{Package: "frodo640aes", TypeName: "Frodo640AES", Ctor: "NewFrodoKEM640AES", N: 640, NBar: 8, MBar: 8, D: 15, B: 2},
+	{Package: "frodo640shake", TypeName: "Frodo640SHAKE", Ctor: "NewFrodoKEM640SHAKE", N: 640, NBar: 8, MBar: 8, D: 15, B: 2},
+	{Package: "frodo976aes", TypeName: "Frodo976AES", Ctor: "NewFrodoKEM976AES", N: 976, NBar: 8, MBar: 8, D: 16, B: 3},
+	{Package: "frodo976shake", TypeName: "Frodo976SHAKE", Ctor: "NewFrodoKEM976SHAKE", N: 976, NBar: 8, MBar: 8, D: 16, B: 3},
+	{Package: "frodo1344aes", TypeName: "Frodo1344AES", Ctor: "NewFrodoKEM1344AES", N: 1344, NBar: 8, MBar: 8, D: 16, B: 4},
+	{Package: "frodo1344shake", TypeName: "Frodo1344SHAKE", Ctor: "NewFrodoKEM1344SHAKE", N: 1344, NBar: 8, MBar: 8, D: 16, B: 4},
+}
+
+var variantTemplate = template.Must(template.New("variant").Parse(`// Code generated by gen.go; DO NOT EDIT.
+
+package {{.Package}}
+
+import frodokem "github.com/JuLi0n21/frodokem-comments"
+
+// Compile-time dimensions for {{.TypeName}}.
+const (
+	N    = {{.N}}
+	NBar = {{.NBar}}
+	MBar = {{.MBar}}
+	D    = {{.D}}
+	B    = {{.B}}
+)
+
+// MatrixA is the n×n public matrix shape for this variant.
+//
+// It is not yet backed by an actual [N][N]uint16 array anywhere on the
+// Keygen/Encapsulate/Decapsulate path below: those still forward straight
+// to the dynamic, []uint16-slice-of-slices frodokem.FrodoKEM core, so
+// today this type (and MatrixSE) only documents the shape a caller should
+// expect, it doesn't get the compiler to inline loop bounds or drop
+// slice-header overhead the way a real array-backed core would. Making
+// that true means teaching frodokem's core itself to operate over
+// [N][N]uint16/[N][NBar]uint16, which is follow-up work, not something
+// this generated facade can retrofit on its own.
+type MatrixA = [N][N]uint16
+
+// MatrixSE is the n×nBar secret/error matrix shape for this variant.
+type MatrixSE = [N][NBar]uint16
+
+// {{.TypeName}} is a thin facade over the dynamic frodokem.FrodoKEM core:
+// it exists so callers who only ever use this one variant get named
+// constants and array *types* instead of bare []uint8/int, not because it
+// changes how Keygen/Encapsulate/Decapsulate compute (see MatrixA's doc).
+type {{.TypeName}} struct {
+	kem *frodokem.FrodoKEM
+}
+
+// New returns a ready-to-use {{.TypeName}}.
+func New() *{{.TypeName}} {
+	return &{{.TypeName}}{kem: frodokem.{{.Ctor}}()}
+}
+
+func (v *{{.TypeName}}) Keygen() (pk []uint8, sk []uint8) {
+	return v.kem.Keygen()
+}
+
+func (v *{{.TypeName}}) Encapsulate(pk []uint8) (ct []uint8, ss []uint8, err error) {
+	return v.kem.Encapsulate(pk)
+}
+
+func (v *{{.TypeName}}) Decapsulate(sk []uint8, ct []uint8) ([]uint8, error) {
+	return v.kem.Dencapsulate(sk, ct)
+}
+`))
+
+func main() {
+	for _, p := range variantParams {
+		if err := os.MkdirAll(p.Package, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		out, err := os.Create(filepath.Join(p.Package, p.Package+".go"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := variantTemplate.Execute(out, p); err != nil {
+			log.Fatal(err)
+		}
+		if err := out.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
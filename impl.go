@@ -2,10 +2,15 @@ package go_frodokem
 
 import (
 	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
 	"encoding/binary"
 	"errors"
+	"strings"
+	"sync"
+
+	"github.com/JuLi0n21/frodokem-comments/internal/gemm"
 	"golang.org/x/crypto/sha3"
-	"math"
 )
 
 // Generate a key-pair
@@ -27,9 +32,6 @@ func (k *FrodoKEM) Keygen() (pk []uint8, sk []uint8) {
 	// Generate seedA by hashing z — seedA is used to create matrix A deterministically
 	seedA := k.shake(z, k.lenSeedA/8)
 
-	// Generate matrix A from seedA (public matrix)
-	A := k.gen(seedA)
-
 	// Generate pseudorandom bitstring used for sampling S and E matrices
 	r := unpackUint16(k.shake(append([]byte{0x5f}, seedSE...), 2*k.n*k.nBar*k.lenChi/8))
 	// Sample Sᵗ (transposed secret matrix) from noise distribution
@@ -41,8 +43,10 @@ func (k *FrodoKEM) Keygen() (pk []uint8, sk []uint8) {
 	// Sample error matrix E from noise distribution
 	E := k.sampleMatrix(r[k.n*k.nBar:2*k.n*k.nBar], k.n, k.nBar)
 
-	// Compute matrix B = AS + E mod q (LWE public component)
-	B := matrixAddWithMod(matrixMulWithMod(A, S, k.q), E, k.q)
+	// Compute matrix B = AS + E mod q (LWE public component). genAndMulWithMod
+	// streams A row by row and folds each row straight into the product, so
+	// the full n×n matrix A is never held in memory at once.
+	B := matrixAddWithMod(k.genAndMulWithMod(seedA, S, k.q), E, k.q)
 
 	// Pack matrix B into byte string
 	b := k.pack(B)
@@ -75,6 +79,20 @@ func (k *FrodoKEM) Keygen() (pk []uint8, sk []uint8) {
 
 // Generate a KEM returning the cipher-text and shared-secret
 func (k *FrodoKEM) Encapsulate(pk []uint8) (ct []uint8, ssEnc []uint8, err error) {
+	ct, session, err := k.encapsulateSession(pk)
+	if err != nil {
+		return
+	}
+	ssEnc = k.shake(append(ct, session.K...), k.lenSS/8)
+	return ct, ssEnc, nil
+}
+
+// encapsulateSession runs the FrodoKEM encapsulation algorithm up to, but
+// not including, the final KDF call, so callers that need to derive more
+// than one sub-key (ExtractSession) or bind a transcript
+// (EncapsulateWithContext, see context.go) can do so without repeating the
+// lattice arithmetic below.
+func (k *FrodoKEM) encapsulateSession(pk []uint8) (ct []uint8, session *Session, err error) {
 	// Step 1: Validate public key length
 	if len(pk) != k.lenSeedA/8+k.d*k.n*k.nBar/8 {
 		err = errors.New("incorrect public key length")
@@ -104,11 +122,11 @@ func (k *FrodoKEM) Encapsulate(pk []uint8) (ct []uint8, ssEnc []uint8, err error
 	Sprime := k.sampleMatrix(r[0:k.mBar*k.n], k.mBar, k.n)            //S'
 	Eprime := k.sampleMatrix(r[k.mBar*k.n:2*k.mBar*k.n], k.mBar, k.n) //E'
 
-	// Step 7: Recompute matrix A from seed
-	A := k.gen(seedA)
-
-	// Step 8: Compute B' = S' * A + E' mod q
-	Bprime := matrixAddWithMod(matrixMulWithMod2(Sprime, A, k.q), Eprime, k.q)
+	// Step 7-8: Compute B' = S' * A + E' mod q. genAndMulWithMod2 streams A
+	// row by row and accumulates straight into the (small) S'·A result, so
+	// matrix A is never fully materialized — see the note on genAndMulWithMod
+	// in Keygen.
+	Bprime := matrixAddWithMod(k.genAndMulWithMod2(seedA, Sprime, k.q), Eprime, k.q)
 	c1 := k.pack(Bprime)
 
 	// Step 9: Sample error matrix E'' (used in computing V)
@@ -117,8 +135,21 @@ func (k *FrodoKEM) Encapsulate(pk []uint8) (ct []uint8, ssEnc []uint8, err error
 	// Step 10: Unpack B (from public key)
 	B := k.unpack(b, k.n, k.nBar)
 
-	// Step 11: Compute V = S' * B + E'' mod q (approximate shared secret)
-	V := matrixAddWithMod(matrixMulWithMod2(Sprime, B, k.q), Eprimeprime, k.q)
+	// Step 11: Compute V = S' * B + E'' mod q (approximate shared secret).
+	// ws.Lock/Unlock brackets the Gemm call and its immediate consumer
+	// because the result aliases ws's reused output buffer until copied into
+	// V by matrixAddWithMod; the lock must stay held until that happens, or
+	// a concurrent Encapsulate/Dencapsulate sharing this *FrodoKEM (e.g. via
+	// a cached Scheme, see scheme.go) could overwrite the buffer first. The
+	// defer (scoped to this closure, not encapsulateSession as a whole) makes
+	// sure a panic inside — matrixAddWithMod panics on shape mismatch — can't
+	// leave ws locked forever for every other caller sharing it.
+	ws := k.workspace()
+	V := func() [][]uint16 {
+		ws.Lock()
+		defer ws.Unlock()
+		return matrixAddWithMod(gemm.GemmSAB(Sprime, B, k.q, ws), Eprimeprime, k.q)
+	}()
 
 	// Step 12: Encode message µ into a matrix and add to V — this is the reconciliation step
 	C := uMatrixAdd(V, k.encode(mu), k.q)
@@ -127,14 +158,23 @@ func (k *FrodoKEM) Encapsulate(pk []uint8) (ct []uint8, ssEnc []uint8, err error
 	// Step 13: Final ciphertext is (c1 || c2)
 	ct = append(c1, c2...)
 
-	// Step 14: Derive shared secret using a KDF over (ct || _k)
-	ssEnc = k.shake(append(ct, _k...), k.lenSS/8)
-
-	return ct, ssEnc, err
+	// Step 14 (KDF over ct || _k) is left to the caller; see Session.
+	return ct, &Session{Mu: mu, K: _k}, nil
 }
 
 // Returns the shared secret by using the provided cipher-text and secret-key
 func (k *FrodoKEM) Dencapsulate(sk []uint8, ct []uint8) (ssDec []uint8, err error) {
+	session, err := k.decapsulateSession(sk, ct)
+	if err != nil {
+		return
+	}
+	ssDec = k.shake(append(ct, session.K...), k.lenSS/8)
+	return ssDec, nil
+}
+
+// decapsulateSession runs the FrodoKEM decapsulation algorithm up to, but
+// not including, the final KDF call; see encapsulateSession.
+func (k *FrodoKEM) decapsulateSession(sk []uint8, ct []uint8) (session *Session, err error) {
 	// Step 1: Verify ciphertext and secret key lengths
 	if len(ct) != k.lenCtBytes {
 		err = errors.New("incorrect cipher length")
@@ -158,11 +198,20 @@ func (k *FrodoKEM) Dencapsulate(sk []uint8, ct []uint8) (ssDec []uint8, err erro
 	//Compute C Unpack c2, n dash and n dash
 	C := k.unpack(c2, k.mBar, k.nBar)
 
-	// Step 5: Compute V' = B' * S
-	BprimeS := matrixMulWithMod(Bprime, S, k.q)
-
-	// Step 6: Reconstruct µ' = decode(C - B'S)
-	M := matrixSubWithMod(C, BprimeS, k.q)
+	// Step 5: Compute V' = B' * S. The workspace lock brackets the Gemm call
+	// through its consumer (matrixSubWithMod) for the same reason as in
+	// encapsulateSession: the result aliases the reused output buffer until
+	// copied out. As there, the defer is scoped to this closure so a panic
+	// inside (matrixSubWithMod panics on shape mismatch) can't wedge ws
+	// locked for every other caller sharing it.
+	ws := k.workspace()
+	M := func() [][]uint16 {
+		ws.Lock()
+		defer ws.Unlock()
+
+		// Step 6: Reconstruct µ' = decode(C - B'S)
+		return matrixSubWithMod(C, gemm.GemmANB(Bprime, S, k.q, ws), k.q)
+	}()
 	//decode m
 	muPrime := k.decode(M)
 
@@ -179,29 +228,31 @@ func (k *FrodoKEM) Dencapsulate(sk []uint8, ct []uint8) (ssDec []uint8, err erro
 	Eprime := k.sampleMatrix(r[k.mBar*k.n:2*k.mBar*k.n], k.mBar, k.n)
 	Eprimeprime := k.sampleMatrix(r[2*k.mBar*k.n:2*k.mBar*k.n+k.mBar*k.nBar], k.mBar, k.nBar)
 
-	// Step 10: Regenerate matrix A and B''
-	A := k.gen(seedA)
-
-	//Caluculate B`` From S`A + E`
-	Bprimeprime := matrixAddWithMod(matrixMulWithMod2(Sprime, A, k.q), Eprime, k.q)
+	// Step 10: Recompute B'' = S'A + E'. genAndMulWithMod2 streams A instead
+	// of regenerating the full matrix, as in Encapsulate.
+	Bprimeprime := matrixAddWithMod(k.genAndMulWithMod2(seedA, Sprime, k.q), Eprime, k.q)
 
 	// Step 11: Recompute V = S' * B + E''
 	B := k.unpack(b, k.n, k.nBar)
-	V := matrixAddWithMod(matrixMulWithMod2(Sprime, B, k.q), Eprimeprime, k.q)
+	V := func() [][]uint16 {
+		ws.Lock()
+		defer ws.Unlock()
+		return matrixAddWithMod(gemm.GemmSAB(Sprime, B, k.q, ws), Eprimeprime, k.q)
+	}()
 
 	// Step 12: Compute expected C' = V + encode(µ')
 	Cprime := uMatrixAdd(V, k.encode(muPrime), k.q)
 
-	// Step 13: Compare received (B', C) with recomputed (B'', C')
-	// Constant-time equality to avoid timing attacks
-	if constantUint16Equals(Bprime, Bprimeprime)+constantUint16Equals(C, Cprime) == 2 {
-		ssDec = k.shake(append(ct, kprime...), k.lenSS/8)
+	// Step 13: Compare received (B', C) with recomputed (B'', C'). Select
+	// between k' (valid ciphertext) and the secret `s` (implicit-rejection
+	// fallback) with a constant-time mask over the whole KDF key instead of
+	// branching on the comparison result, so the two code paths are
+	// indistinguishable by timing.
+	matches := constantUint16Equals(Bprime, Bprimeprime) + constantUint16Equals(C, Cprime)
+	valid := subtle.ConstantTimeEq(int32(matches), 2)
+	kdfKey := constantTimeSelectBytes(valid, kprime, s)
 
-		// Invalid ciphertext: fallback to secret `s` to derive shared secret
-	} else {
-		ssDec = k.shake(append(ct, s...), k.lenSS/8)
-	}
-	return ssDec, err
+	return &Session{Mu: muPrime, K: kdfKey}, nil
 }
 
 // -------------------------------------------------------- OTHER FUNCTIONS USED UNCOMMENTATED --------------------------------------------------------------------------------------------
@@ -230,9 +281,39 @@ func (k *FrodoKEM) CipherTextLen() int {
 	return k.lenCtBytes
 }
 
-// Overrides the default random number generator (crypto/rand)
-func (k *FrodoKEM) OverrideRng(newRng func([]byte)) {
+// Overrides the default random number generator (crypto/rand). The returned
+// func restores the previous rng, so callers that need determinism for a
+// single call can `defer` it instead of hand-rolling the save/restore
+// themselves.
+func (k *FrodoKEM) OverrideRng(newRng func([]byte)) (restore func()) {
+	previous := k.rng
 	k.rng = newRng
+	return func() {
+		k.rng = previous
+	}
+}
+
+// wsInitMu guards the lazy-init check in workspace below. It's a single
+// package-level lock rather than one per FrodoKEM because it only ever
+// protects the "is k.ws nil" race on first use, not the workspace's buffers
+// themselves — those are guarded by the *gemm.Workspace's own Lock/Unlock,
+// which callers must hold around any Gemm call and its immediate consumer
+// (see encapsulateSession/decapsulateSession).
+var wsInitMu sync.Mutex
+
+// workspace lazily initializes k's gemm.Workspace on first use, so the
+// GemmANB/GemmSAB output buffers are reused across every Encapsulate and
+// Dencapsulate call made on this FrodoKEM instance instead of being
+// allocated fresh each time. This matters because a Scheme (scheme.go) hands
+// out one shared *FrodoKEM per variant name, so workspace() itself — and the
+// buffers it returns — can be reached concurrently.
+func (k *FrodoKEM) workspace() *gemm.Workspace {
+	wsInitMu.Lock()
+	defer wsInitMu.Unlock()
+	if k.ws == nil {
+		k.ws = &gemm.Workspace{}
+	}
+	return k.ws
 }
 
 func (k *FrodoKEM) unwrapCt(ct []uint8) (c1 []uint8, c2 []uint8) {
@@ -280,19 +361,28 @@ func (k *FrodoKEM) unwrapSk(sk []uint8) (s []uint8, seedA []uint8, b []uint8, St
 	return
 }
 
+// sample draws one coefficient from the FrodoKEM error distribution, encoded
+// by r as (sign bit || 15-bit CDF sample). It must run in constant time
+// because r is derived from the secret seedSE, so the table walk below never
+// branches on a secret value: every CDF entry but the last is inspected on
+// every call and its contribution is folded in via a mask instead of an `if`.
 func (k *FrodoKEM) sample(r uint16) (e int16) {
-	t := int(r >> 1)
-	e = 0
+	t := int32(r >> 1)
+	var acc int32
 	for z := 0; z < len(k.tChi)-1; z++ {
-		if t > int(k.tChi[z]) {
-			e += 1
-		}
-	}
-	r0 := r % 2
-	if r0 == 1 {
-		e = -e
-	}
-	return
+		// diff is negative exactly when tChi[z] < t, i.e. when the original
+		// `t > tChi[z]` branch would have fired; uint32(diff)>>31 turns that
+		// sign bit into a 0/1 contribution without a conditional.
+		diff := int32(k.tChi[z]) - t
+		acc += int32(uint32(diff) >> 31)
+	}
+
+	// Apply the sign carried in r's low bit via a constant-time mask instead
+	// of `if r0 == 1 { e = -e }`.
+	sign := int32(r & 1)
+	mask := -sign
+	acc = (mask ^ acc) + sign
+	return int16(acc)
 }
 
 func (k *FrodoKEM) sampleMatrix(r []uint16, n1 int, n2 int) (E [][]int16) {
@@ -388,19 +478,21 @@ func (k *FrodoKEM) encode(b []uint8) (K [][]uint16) {
 }
 
 // FrodoKEM specification, Algorithm 2
+//
+// Since q is always a power of two (q = 2^k.d), round(K*2^b/q) reduces to an
+// integer shift: v = (K + q>>(b+1)) >> (d-b), where q>>(b+1) is the rounding
+// term (half of the bucket width 2^(d-b)). This avoids the float64 rounding
+// the previous implementation did over secret-dependent data.
 func (k *FrodoKEM) decode(K [][]uint16) (b []uint8) {
 	b = make([]uint8, k.b*k.mBar*k.nBar/8)
-	fixedQ := float64(k.q)
-	if k.q == 0 {
-		fixedQ = float64(65535)
-	}
-	twoPowerB := int32(2 << (k.b - 1))
-	twoPowerBf := float64(int(2 << (k.b - 1)))
+	rounding := uint32(1) << uint(k.d-k.b-1)
+	shift := uint(k.d - k.b)
+	mask := uint32(1)<<uint(k.b) - 1
 	bIdx := 0
 	BBit := 0
 	for i := 0; i < k.mBar; i++ {
 		for j := 0; j < k.nBar; j++ {
-			tmp := uint8(int32(math.Round(float64(K[i][j])*twoPowerBf/fixedQ)) % twoPowerB) //FIXME: please do this better
+			tmp := uint8((uint32(K[i][j])+rounding)>>shift) & uint8(mask)
 			for l := 0; l < k.b; l++ {
 				if uint8BitN(tmp, l) == 1 {
 					b[bIdx] = uint8setBitN(b[bIdx], BBit)
@@ -416,46 +508,169 @@ func (k *FrodoKEM) decode(K [][]uint16) (b []uint8) {
 	return
 }
 
-func (k *FrodoKEM) genSHAKE128(seedA []byte) (A [][]uint16) {
-	var c = make([]byte, 2*k.n)
-	var tmp = make([]byte, 2+len(seedA))
+// genRowSHAKE128 generates matrix A row by row via SHAKE128(i || seedA),
+// invoking row once per generated row instead of building the full n×n
+// matrix. It reuses a single sha3.ShakeHash and output buffer across rows
+// (Reset+Write+Read) rather than sha3.ShakeSum128's one-shot allocation per
+// row, and is the basis for the fused genAndMulWithMod* helpers below.
+func (k *FrodoKEM) genRowSHAKE128(seedA []byte, row func(i int, a []uint16)) {
+	h := sha3.NewShake128()
+	out := make([]byte, 2*k.n)
+	tmp := make([]byte, 2+len(seedA))
 	copy(tmp[2:], seedA)
-	A = make([][]uint16, k.n)
+	a := make([]uint16, k.n)
 	for i := 0; i < k.n; i++ {
-		A[i] = make([]uint16, k.n)
 		binary.LittleEndian.PutUint16(tmp[0:], uint16(i))
-		sha3.ShakeSum128(c, tmp)
+		h.Reset()
+		h.Write(tmp)
+		h.Read(out)
 		for j := 0; j < k.n; j++ {
-			A[i][j] = binary.LittleEndian.Uint16(c[j*2 : (j+1)*2])
+			a[j] = binary.LittleEndian.Uint16(out[j*2 : (j+1)*2])
 			if k.q != 0 {
-				A[i][j] %= k.q
+				a[j] %= k.q
 			}
 		}
+		row(i, a)
+	}
+}
+
+// ecbEncrypter implements cipher.BlockMode as independent, unchained AES
+// block encryptions, so genRowAES128 can hand a whole row's counter blocks
+// to CryptBlocks in one call. CryptBlocks itself is a Go loop that calls
+// cipher.Block.Encrypt once per 16-byte block; it does not use a multi-block
+// AES path. Every block genRowAES128 encrypts is a fresh,
+// public (i, j) counter rather than secret data, so there's no
+// confidentiality reason to chain blocks the way CBC/CTR do — ECB here is
+// a batching device, not a mode choice with a security tradeoff.
+type ecbEncrypter struct {
+	block cipher.Block
+}
+
+func newECBEncrypter(block cipher.Block) cipher.BlockMode {
+	return &ecbEncrypter{block: block}
+}
+
+func (e *ecbEncrypter) BlockSize() int { return e.block.BlockSize() }
+
+func (e *ecbEncrypter) CryptBlocks(dst, src []byte) {
+	bs := e.block.BlockSize()
+	if len(src)%bs != 0 {
+		panic("ecbEncrypter: input not a multiple of the block size")
+	}
+	if len(dst) < len(src) {
+		panic("ecbEncrypter: output smaller than input")
+	}
+	for len(src) > 0 {
+		e.block.Encrypt(dst, src[:bs])
+		src = src[bs:]
+		dst = dst[bs:]
 	}
-	return
 }
 
-func (k *FrodoKEM) genAES128(seedA []byte) (A [][]uint16) {
-	A = make([][]uint16, k.n)
-	cipher, err := aes.NewCipher(seedA)
+// genRowAES128 generates matrix A row by row via AES128(seedA, i || j),
+// invoking row once per generated row instead of building the full n×n
+// matrix. It is the basis for the fused genAndMulWithMod* helpers below.
+//
+// All n/8 counter blocks for a row are assembled into one contiguous
+// buffer and encrypted with a single ecbEncrypter.CryptBlocks call. That
+// call still encrypts the blocks one at a time through cipher.Block.Encrypt,
+// so the AES work per block equals a direct Encrypt call per block; what the
+// single call adds is one contiguous buffer for a row's counter blocks.
+func (k *FrodoKEM) genRowAES128(seedA []byte, row func(i int, a []uint16)) {
+	block, err := aes.NewCipher(seedA)
 	if err != nil {
 		panic(err)
 	}
-	var b = [16]byte{}
-	var c = [16]byte{}
+	ecb := newECBEncrypter(block)
+
+	blocksPerRow := k.n / 8
+	buf := make([]byte, blocksPerRow*16)
+	a := make([]uint16, k.n)
 	for i := 0; i < k.n; i++ {
-		A[i] = make([]uint16, k.n)
-		for j := 0; j < k.n; j += 8 {
+		for blk := 0; blk < blocksPerRow; blk++ {
+			b := buf[blk*16 : blk*16+16]
+			for x := range b {
+				b[x] = 0
+			}
 			binary.LittleEndian.PutUint16(b[0:2], uint16(i))
-			binary.LittleEndian.PutUint16(b[2:4], uint16(j))
-			cipher.Encrypt(c[:], b[:])
+			binary.LittleEndian.PutUint16(b[2:4], uint16(blk*8))
+		}
+		ecb.CryptBlocks(buf, buf)
+		for blk := 0; blk < blocksPerRow; blk++ {
+			c := buf[blk*16 : blk*16+16]
+			j := blk * 8
 			for l := 0; l < 8; l++ {
-				A[i][j+l] = binary.LittleEndian.Uint16(c[l*2 : (l+1)*2])
+				a[j+l] = binary.LittleEndian.Uint16(c[l*2 : (l+1)*2])
 				if k.q != 0 {
-					A[i][j+l] %= k.q
+					a[j+l] %= k.q
 				}
 			}
+		}
+		row(i, a)
+	}
+}
+
+// genRow dispatches to the row-streaming matrix-A generator matching this
+// variant's name (genRowAES128 or genRowSHAKE128). It's a name-based
+// dispatch, rather than a func field set by the NewFrodoKEM* constructors,
+// so genAndMulWithMod/genAndMulWithMod2 work for every variant without
+// requiring changes to those (unmodified-by-this-package) constructors.
+func (k *FrodoKEM) genRow(seedA []byte, row func(i int, a []uint16)) {
+	if strings.Contains(k.name, "SHAKE") {
+		k.genRowSHAKE128(seedA, row)
+		return
+	}
+	k.genRowAES128(seedA, row)
+}
+
+// genAndMulWithMod streams matrix A row by row via k.genRow and multiplies
+// it against S in place, computing A·S mod q without ever holding all of A
+// (up to ~3.6 MB for Frodo1344) in memory at once — row i of A produces row
+// i of the result directly.
+func (k *FrodoKEM) genAndMulWithMod(seedA []byte, S [][]int16, q uint16) (R [][]uint16) {
+	ncols := len(S[0])
+	R = make([][]uint16, k.n)
+	k.genRow(seedA, func(i int, a []uint16) {
+		out := make([]uint16, ncols)
+		for j := 0; j < ncols; j++ {
+			var res uint16
+			for kk := 0; kk < k.n; kk++ {
+				res += uint16(int16(a[kk]) * S[kk][j])
+			}
+			if q != 0 {
+				res %= q
+			}
+			out[j] = res
+		}
+		R[i] = out
+	})
+	return
+}
 
+// genAndMulWithMod2 streams matrix A row by row via k.genRow and multiplies
+// S (mBar×n) against it, computing S·A mod q. Unlike genAndMulWithMod, every
+// streamed row of A contributes to every row of the (small, mBar×n) result,
+// so the accumulator is built up incrementally across the whole stream
+// instead of being produced one output row at a time.
+func (k *FrodoKEM) genAndMulWithMod2(seedA []byte, S [][]int16, q uint16) (R [][]uint16) {
+	nrows := len(S)
+	R = make([][]uint16, nrows)
+	for i := 0; i < nrows; i++ {
+		R[i] = make([]uint16, k.n)
+	}
+	k.genRow(seedA, func(kk int, a []uint16) {
+		for i := 0; i < nrows; i++ {
+			sik := S[i][kk]
+			for j := 0; j < k.n; j++ {
+				R[i][j] += uint16(sik * int16(a[j]))
+			}
+		}
+	})
+	if q != 0 {
+		for i := 0; i < nrows; i++ {
+			for j := 0; j < k.n; j++ {
+				R[i][j] %= q
+			}
 		}
 	}
 	return
@@ -480,6 +695,20 @@ func constantUint16Equals(a [][]uint16, b [][]uint16) (ret int) {
 	return
 }
 
+// constantTimeSelectBytes returns a (copy of) a if cond == 1, or b if
+// cond == 0, selecting byte-by-byte via subtle.ConstantTimeSelect so the
+// choice doesn't depend on a secret-dependent branch.
+func constantTimeSelectBytes(cond int, a []byte, b []byte) []byte {
+	if len(a) != len(b) {
+		panic("constantTimeSelectBytes: mismatched lengths")
+	}
+	out := make([]byte, len(a))
+	for i := range out {
+		out[i] = byte(subtle.ConstantTimeSelect(cond, int(a[i]), int(b[i])))
+	}
+	return out
+}
+
 func matrixAddWithMod(X [][]uint16, Y [][]int16, q uint16) (R [][]uint16) {
 	nrowsx := len(X)
 	ncolsx := len(X[0])
@@ -543,50 +772,6 @@ func matrixSubWithMod(X [][]uint16, Y [][]uint16, q uint16) (R [][]uint16) {
 	return
 }
 
-func matrixMulWithMod(X [][]uint16, Y [][]int16, q uint16) (R [][]uint16) {
-	nrowsx := len(X)
-	ncolsx := len(X[0])
-	//nrowsy := len(y)
-	ncolsy := len(Y[0])
-	R = make([][]uint16, nrowsx)
-	for i := 0; i < nrowsx; i++ {
-		R[i] = make([]uint16, ncolsy)
-		for j := 0; j < ncolsy; j++ {
-			var res uint16
-			for k := 0; k < ncolsx; k++ {
-				res += uint16(int16(X[i][k]) * Y[k][j])
-			}
-			if q != 0 {
-				res %= q
-			}
-			R[i][j] = res
-		}
-	}
-	return
-}
-
-func matrixMulWithMod2(X [][]int16, Y [][]uint16, q uint16) (R [][]uint16) {
-	nrowsx := len(X)
-	ncolsx := len(X[0])
-	//nrowsy := len(y)
-	ncolsy := len(Y[0])
-	R = make([][]uint16, nrowsx)
-	for i := 0; i < nrowsx; i++ {
-		R[i] = make([]uint16, ncolsy)
-		for j := 0; j < ncolsy; j++ {
-			var res uint16
-			for k := 0; k < ncolsx; k++ {
-				res += uint16(X[i][k] * int16(Y[k][j]))
-			}
-			if q != 0 {
-				res %= q
-			}
-			R[i][j] = res
-		}
-	}
-	return
-}
-
 func matrixTranspose(O [][]int16) (T [][]int16) {
 	T = make([][]int16, len(O[0]))
 	for x := 0; x < len(T); x++ {
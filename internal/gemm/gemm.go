@@ -0,0 +1,169 @@
+// Package gemm implements the two matrix-multiply shapes FrodoKEM needs
+// (X·Y and X'·Y, where one operand is always the wide n-ish dimension) with
+// 32×32 tiling over the shared dimension, so the inner loop's working set
+// fits in L1 cache instead of striding across the whole matrix.
+//
+// Both kernels accumulate into uint16 and take the modulus once at the end,
+// same as the matrixMulWithMod/matrixMulWithMod2 functions they replace:
+// for Frodo976/Frodo1344 (q represented as 0, i.e. q = 2^16) the uint16
+// wraparound of the accumulator *is* the reduction, so no modular cast is
+// needed mid-loop.
+//
+// A hand-written AVX2 kernel (16 lanes of int16 multiply-accumulate per
+// instruction) was considered per the original request, but is left for a
+// follow-up change: verifying SIMD assembly correctness across Frodo's
+// several parameter sets needs a real build/test environment to check
+// against, which isn't available here.
+package gemm
+
+import "sync"
+
+const tileSize = 32
+
+// Workspace holds the output buffers for GemmANB/GemmSAB so that repeated
+// calls against matrices of the same shape (as happens on every
+// Encapsulate/Decapsulate call for a given FrodoKEM instance) reuse them
+// instead of allocating fresh ones. The zero value is ready to use.
+//
+// A Workspace's buffers may be shared by concurrent callers (a Scheme hands
+// out one *FrodoKEM, and so one Workspace, per variant name). Lock/Unlock
+// guard that sharing: callers must hold the lock from the GemmANB/GemmSAB
+// call through whatever immediately consumes its result, since the returned
+// slice aliases the reused buffer until then.
+type Workspace struct {
+	mu  sync.Mutex
+	anb [][]uint16
+	sab [][]uint16
+}
+
+// Lock acquires exclusive access to w's buffers.
+func (w *Workspace) Lock() { w.mu.Lock() }
+
+// Unlock releases exclusive access to w's buffers.
+func (w *Workspace) Unlock() { w.mu.Unlock() }
+
+func (w *Workspace) outANB(rows, cols int) [][]uint16 {
+	out := reuseOrAlloc(w.anb, rows, cols)
+	w.anb = out
+	return out
+}
+
+func (w *Workspace) outSAB(rows, cols int) [][]uint16 {
+	out := reuseOrAlloc(w.sab, rows, cols)
+	w.sab = out
+	return out
+}
+
+func reuseOrAlloc(buf [][]uint16, rows, cols int) [][]uint16 {
+	if len(buf) != rows || (rows > 0 && len(buf[0]) != cols) {
+		buf = make([][]uint16, rows)
+		for i := range buf {
+			buf[i] = make([]uint16, cols)
+		}
+		return buf
+	}
+	for i := 0; i < rows; i++ {
+		row := buf[i]
+		for j := range row {
+			row[j] = 0
+		}
+	}
+	return buf
+}
+
+// GemmANB computes R = X·Y mod q (X: rows×inner, Y: inner×cols), tiling the
+// shared "inner" dimension in 32-wide blocks so the Y rows touched by an
+// inner-loop pass stay resident in L1. ws may be nil, in which case a fresh
+// result matrix is allocated.
+func GemmANB(X [][]uint16, Y [][]int16, q uint16, ws *Workspace) [][]uint16 {
+	rows := len(X)
+	inner := len(Y)
+	cols := len(Y[0])
+
+	var R [][]uint16
+	if ws != nil {
+		R = ws.outANB(rows, cols)
+	} else {
+		R = reuseOrAlloc(nil, rows, cols)
+	}
+
+	for ii := 0; ii < rows; ii += tileSize {
+		iEnd := min(ii+tileSize, rows)
+		for kk := 0; kk < inner; kk += tileSize {
+			kEnd := min(kk+tileSize, inner)
+			for i := ii; i < iEnd; i++ {
+				Xi := X[i]
+				Ri := R[i]
+				for k := kk; k < kEnd; k++ {
+					x := int16(Xi[k])
+					Yk := Y[k]
+					for j := 0; j < cols; j++ {
+						Ri[j] += uint16(x * Yk[j])
+					}
+				}
+			}
+		}
+	}
+
+	if q != 0 {
+		for i := 0; i < rows; i++ {
+			Ri := R[i]
+			for j := range Ri {
+				Ri[j] %= q
+			}
+		}
+	}
+	return R
+}
+
+// GemmSAB computes R = X·Y mod q (X: rows×inner, Y: inner×cols), tiling the
+// shared "inner" dimension the same way as GemmANB. It is the X [][]int16 /
+// Y [][]uint16 twin of GemmANB, matching the S'·A and S'·B shapes FrodoKEM
+// needs during Encapsulate/Decapsulate.
+func GemmSAB(X [][]int16, Y [][]uint16, q uint16, ws *Workspace) [][]uint16 {
+	rows := len(X)
+	inner := len(Y)
+	cols := len(Y[0])
+
+	var R [][]uint16
+	if ws != nil {
+		R = ws.outSAB(rows, cols)
+	} else {
+		R = reuseOrAlloc(nil, rows, cols)
+	}
+
+	for ii := 0; ii < rows; ii += tileSize {
+		iEnd := min(ii+tileSize, rows)
+		for kk := 0; kk < inner; kk += tileSize {
+			kEnd := min(kk+tileSize, inner)
+			for i := ii; i < iEnd; i++ {
+				Xi := X[i]
+				Ri := R[i]
+				for k := kk; k < kEnd; k++ {
+					x := Xi[k]
+					Yk := Y[k]
+					for j := 0; j < cols; j++ {
+						Ri[j] += uint16(x * int16(Yk[j]))
+					}
+				}
+			}
+		}
+	}
+
+	if q != 0 {
+		for i := 0; i < rows; i++ {
+			Ri := R[i]
+			for j := range Ri {
+				Ri[j] %= q
+			}
+		}
+	}
+	return R
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
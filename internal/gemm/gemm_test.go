@@ -0,0 +1,221 @@
+package gemm
+
+import "testing"
+
+// naiveANB computes the same shape as GemmANB with a straightforward
+// triple loop, to check the tiled kernel against on non-tile-aligned sizes.
+func naiveANB(X [][]uint16, Y [][]int16, q uint16) [][]uint16 {
+	rows := len(X)
+	inner := len(Y)
+	cols := len(Y[0])
+	R := make([][]uint16, rows)
+	for i := 0; i < rows; i++ {
+		R[i] = make([]uint16, cols)
+		for k := 0; k < inner; k++ {
+			x := int16(X[i][k])
+			for j := 0; j < cols; j++ {
+				R[i][j] += uint16(x * Y[k][j])
+			}
+		}
+		if q != 0 {
+			for j := 0; j < cols; j++ {
+				R[i][j] %= q
+			}
+		}
+	}
+	return R
+}
+
+func naiveSAB(X [][]int16, Y [][]uint16, q uint16) [][]uint16 {
+	rows := len(X)
+	inner := len(Y)
+	cols := len(Y[0])
+	R := make([][]uint16, rows)
+	for i := 0; i < rows; i++ {
+		R[i] = make([]uint16, cols)
+		for k := 0; k < inner; k++ {
+			x := X[i][k]
+			for j := 0; j < cols; j++ {
+				R[i][j] += uint16(x * int16(Y[k][j]))
+			}
+		}
+		if q != 0 {
+			for j := 0; j < cols; j++ {
+				R[i][j] %= q
+			}
+		}
+	}
+	return R
+}
+
+// lcg is a tiny deterministic pseudo-random source so test matrices don't
+// depend on math/rand's seeding behavior.
+func lcg(seed uint32) func() uint32 {
+	state := seed
+	return func() uint32 {
+		state = state*1664525 + 1013904223
+		return state
+	}
+}
+
+func randANBMatrices(rows, inner, cols int, seed uint32) ([][]uint16, [][]int16) {
+	next := lcg(seed)
+	X := make([][]uint16, rows)
+	for i := range X {
+		X[i] = make([]uint16, inner)
+		for k := range X[i] {
+			X[i][k] = uint16(next())
+		}
+	}
+	Y := make([][]int16, inner)
+	for k := range Y {
+		Y[k] = make([]int16, cols)
+		for j := range Y[k] {
+			Y[k][j] = int16(next())
+		}
+	}
+	return X, Y
+}
+
+func randSABMatrices(rows, inner, cols int, seed uint32) ([][]int16, [][]uint16) {
+	next := lcg(seed)
+	X := make([][]int16, rows)
+	for i := range X {
+		X[i] = make([]int16, inner)
+		for k := range X[i] {
+			X[i][k] = int16(next())
+		}
+	}
+	Y := make([][]uint16, inner)
+	for k := range Y {
+		Y[k] = make([]uint16, cols)
+		for j := range Y[k] {
+			Y[k][j] = uint16(next())
+		}
+	}
+	return X, Y
+}
+
+func equalMatrices(a, b [][]uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TestGemmANBTileBoundaries checks GemmANB against a naive reference across
+// dimensions that straddle, undershoot and overshoot the 32-wide tile size,
+// since a tiling bug is most likely to show up at a partial final tile.
+func TestGemmANBTileBoundaries(t *testing.T) {
+	dims := []struct{ rows, inner, cols int }{
+		{1, 1, 1},
+		{8, 8, 8},
+		{31, 31, 31},
+		{32, 32, 32},
+		{33, 33, 33},
+		{40, 65, 17},
+		{3, 100, 3},
+	}
+	for _, d := range dims {
+		X, Y := randANBMatrices(d.rows, d.inner, d.cols, 1)
+		got := GemmANB(X, Y, 0, nil)
+		want := naiveANB(X, Y, 0)
+		if !equalMatrices(got, want) {
+			t.Errorf("GemmANB(%dx%dx%d): result mismatch against naive reference", d.rows, d.inner, d.cols)
+		}
+
+		// Same shapes again with a modulus, since the q!=0 reduction pass is
+		// a separate code path from the q==0 raw-wraparound one.
+		X, Y = randANBMatrices(d.rows, d.inner, d.cols, 2)
+		got = GemmANB(X, Y, 10007, nil)
+		want = naiveANB(X, Y, 10007)
+		if !equalMatrices(got, want) {
+			t.Errorf("GemmANB(%dx%dx%d) mod q: result mismatch against naive reference", d.rows, d.inner, d.cols)
+		}
+	}
+}
+
+// TestGemmSABTileBoundaries is TestGemmANBTileBoundaries's twin for GemmSAB.
+func TestGemmSABTileBoundaries(t *testing.T) {
+	dims := []struct{ rows, inner, cols int }{
+		{1, 1, 1},
+		{8, 8, 8},
+		{31, 31, 31},
+		{32, 32, 32},
+		{33, 33, 33},
+		{17, 40, 65},
+	}
+	for _, d := range dims {
+		X, Y := randSABMatrices(d.rows, d.inner, d.cols, 3)
+		got := GemmSAB(X, Y, 0, nil)
+		want := naiveSAB(X, Y, 0)
+		if !equalMatrices(got, want) {
+			t.Errorf("GemmSAB(%dx%dx%d): result mismatch against naive reference", d.rows, d.inner, d.cols)
+		}
+
+		X, Y = randSABMatrices(d.rows, d.inner, d.cols, 4)
+		got = GemmSAB(X, Y, 10007, nil)
+		want = naiveSAB(X, Y, 10007)
+		if !equalMatrices(got, want) {
+			t.Errorf("GemmSAB(%dx%dx%d) mod q: result mismatch against naive reference", d.rows, d.inner, d.cols)
+		}
+	}
+}
+
+// TestWorkspaceReuse checks that reusing a Workspace across calls of the
+// same shape doesn't leak a previous call's accumulator contents into the
+// next one (reuseOrAlloc must zero the buffer it hands back).
+func TestWorkspaceReuse(t *testing.T) {
+	ws := &Workspace{}
+
+	X1, Y1 := randANBMatrices(9, 9, 9, 5)
+	want1 := naiveANB(X1, Y1, 0)
+	ws.Lock()
+	got1 := GemmANB(X1, Y1, 0, ws)
+	got1Copy := make([][]uint16, len(got1))
+	for i := range got1 {
+		got1Copy[i] = append([]uint16(nil), got1[i]...)
+	}
+	ws.Unlock()
+	if !equalMatrices(got1Copy, want1) {
+		t.Fatalf("GemmANB via Workspace: first call mismatch")
+	}
+
+	X2, Y2 := randANBMatrices(9, 9, 9, 6)
+	want2 := naiveANB(X2, Y2, 0)
+	ws.Lock()
+	got2 := GemmANB(X2, Y2, 0, ws)
+	got2Copy := make([][]uint16, len(got2))
+	for i := range got2 {
+		got2Copy[i] = append([]uint16(nil), got2[i]...)
+	}
+	ws.Unlock()
+	if !equalMatrices(got2Copy, want2) {
+		t.Fatalf("GemmANB via Workspace: second call (reused buffer) mismatch — old accumulator not cleared")
+	}
+
+	// A shape change must not panic or silently truncate; the workspace
+	// should just reallocate.
+	X3, Y3 := randANBMatrices(5, 12, 20, 7)
+	want3 := naiveANB(X3, Y3, 0)
+	ws.Lock()
+	got3 := GemmANB(X3, Y3, 0, ws)
+	got3Copy := make([][]uint16, len(got3))
+	for i := range got3 {
+		got3Copy[i] = append([]uint16(nil), got3[i]...)
+	}
+	ws.Unlock()
+	if !equalMatrices(got3Copy, want3) {
+		t.Fatalf("GemmANB via Workspace: call after shape change mismatch")
+	}
+}
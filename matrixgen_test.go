@@ -0,0 +1,202 @@
+package go_frodokem
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"testing"
+)
+
+// naiveMulAS computes A·S mod q the straightforward way, for comparison
+// against the streaming genAndMulWithMod.
+func naiveMulAS(A [][]uint16, S [][]int16, q uint16) [][]uint16 {
+	n := len(A)
+	nBar := len(S[0])
+	R := make([][]uint16, n)
+	for i := 0; i < n; i++ {
+		R[i] = make([]uint16, nBar)
+		for k := 0; k < n; k++ {
+			a := int16(A[i][k])
+			for j := 0; j < nBar; j++ {
+				R[i][j] += uint16(a * S[k][j])
+			}
+		}
+		if q != 0 {
+			for j := range R[i] {
+				R[i][j] %= q
+			}
+		}
+	}
+	return R
+}
+
+// naiveMulSA computes S·A mod q (S: mBar×n, A: n×n), for comparison against
+// the streaming genAndMulWithMod2.
+func naiveMulSA(S [][]int16, A [][]uint16, q uint16) [][]uint16 {
+	mBar := len(S)
+	n := len(A)
+	R := make([][]uint16, mBar)
+	for i := 0; i < mBar; i++ {
+		R[i] = make([]uint16, n)
+		for k := 0; k < n; k++ {
+			s := S[i][k]
+			for j := 0; j < n; j++ {
+				R[i][j] += uint16(s * int16(A[k][j]))
+			}
+		}
+		if q != 0 {
+			for j := range R[i] {
+				R[i][j] %= q
+			}
+		}
+	}
+	return R
+}
+
+// genFullMatrix materializes the full n×n matrix A via k.genRow, for use as
+// a reference in tests — genAndMulWithMod/genAndMulWithMod2 must agree with
+// multiplying against this, since both stream the same rows k.genRow would
+// produce here.
+func genFullMatrix(k *FrodoKEM, seedA []byte) [][]uint16 {
+	A := make([][]uint16, k.n)
+	k.genRow(seedA, func(i int, a []uint16) {
+		A[i] = append([]uint16(nil), a...)
+	})
+	return A
+}
+
+// TestGenAndMulWithModMatchesNaive checks that the fused, streaming A·S
+// (genAndMulWithMod) agrees with multiplying S against a fully-materialized
+// A generated by the same k.genRow rows.
+func TestGenAndMulWithModMatchesNaive(t *testing.T) {
+	k := NewFrodoKEM640AES()
+	seedA := make([]byte, k.lenSeedA/8)
+	if _, err := rand.Read(seedA); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	S := make([][]int16, k.n)
+	for i := range S {
+		S[i] = make([]int16, k.nBar)
+		for j := range S[i] {
+			S[i][j] = int16(i*7 + j*13)
+		}
+	}
+
+	A := genFullMatrix(k, seedA)
+	want := naiveMulAS(A, S, k.q)
+	got := k.genAndMulWithMod(seedA, S, k.q)
+
+	for i := range want {
+		if !equalUint16Rows(want[i], got[i]) {
+			t.Fatalf("genAndMulWithMod row %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestGenAndMulWithMod2MatchesNaive is TestGenAndMulWithModMatchesNaive's
+// twin for the S'·A direction (genAndMulWithMod2).
+func TestGenAndMulWithMod2MatchesNaive(t *testing.T) {
+	k := NewFrodoKEM640AES()
+	seedA := make([]byte, k.lenSeedA/8)
+	if _, err := rand.Read(seedA); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	S := make([][]int16, k.mBar)
+	for i := range S {
+		S[i] = make([]int16, k.n)
+		for j := range S[i] {
+			S[i][j] = int16(i*11 + j*3)
+		}
+	}
+
+	A := genFullMatrix(k, seedA)
+	want := naiveMulSA(S, A, k.q)
+	got := k.genAndMulWithMod2(seedA, S, k.q)
+
+	for i := range want {
+		if !equalUint16Rows(want[i], got[i]) {
+			t.Fatalf("genAndMulWithMod2 row %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func equalUint16Rows(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestGenRowDispatch checks that k.genRow picks genRowAES128 for an AES
+// variant and genRowSHAKE128 for a SHAKE one, rather than silently always
+// using one or the other.
+func TestGenRowDispatch(t *testing.T) {
+	kAES := NewFrodoKEM640AES()
+	kSHAKE := NewFrodoKEM640SHAKE()
+	seedA := bytes.Repeat([]byte{0x07}, kAES.lenSeedA/8)
+
+	var viaDispatch, viaDirect [][]uint16
+	kAES.genRow(seedA, func(i int, a []uint16) {
+		viaDispatch = append(viaDispatch, append([]uint16(nil), a...))
+	})
+	kAES.genRowAES128(seedA, func(i int, a []uint16) {
+		viaDirect = append(viaDirect, append([]uint16(nil), a...))
+	})
+	for i := range viaDirect {
+		if !equalUint16Rows(viaDispatch[i], viaDirect[i]) {
+			t.Fatalf("genRow on an AES variant diverged from genRowAES128 at row %d", i)
+		}
+	}
+
+	viaDispatch = nil
+	var viaDirectShake [][]uint16
+	kSHAKE.genRow(seedA, func(i int, a []uint16) {
+		viaDispatch = append(viaDispatch, append([]uint16(nil), a...))
+	})
+	kSHAKE.genRowSHAKE128(seedA, func(i int, a []uint16) {
+		viaDirectShake = append(viaDirectShake, append([]uint16(nil), a...))
+	})
+	for i := range viaDirectShake {
+		if !equalUint16Rows(viaDispatch[i], viaDirectShake[i]) {
+			t.Fatalf("genRow on a SHAKE variant diverged from genRowSHAKE128 at row %d", i)
+		}
+	}
+}
+
+// TestECBEncrypterMatchesPerBlockEncrypt checks that batching a row's
+// counter blocks through ecbEncrypter.CryptBlocks produces the same bytes
+// as encrypting each block individually with cipher.Block.Encrypt — i.e.
+// the batching in genRowAES128 is purely a call-pattern change, not a
+// different cipher construction.
+func TestECBEncrypterMatchesPerBlockEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, 16)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("aes.NewCipher: %v", err)
+	}
+
+	const numBlocks = 37
+	src := make([]byte, numBlocks*16)
+	if _, err := rand.Read(src); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	want := make([]byte, len(src))
+	for i := 0; i < numBlocks; i++ {
+		block.Encrypt(want[i*16:(i+1)*16], src[i*16:(i+1)*16])
+	}
+
+	got := make([]byte, len(src))
+	newECBEncrypter(block).CryptBlocks(got, src)
+
+	if !bytes.Equal(want, got) {
+		t.Fatal("ecbEncrypter.CryptBlocks does not match per-block cipher.Block.Encrypt")
+	}
+}
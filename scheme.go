@@ -0,0 +1,272 @@
+package go_frodokem
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Scheme is a CIRCL-style, name-addressable handle onto a FrodoKEM variant.
+// It exposes a uniform key-pair / encapsulation API so callers can plug a
+// variant into hybrid KEM constructions without reaching into FrodoKEM's
+// own []uint8 wire format.
+type Scheme interface {
+	// Name returns the variant name, e.g. "FrodoKEM-640-AES".
+	Name() string
+
+	// PublicKeySize and PrivateKeySize report the marshaled key sizes in bytes.
+	PublicKeySize() int
+	PrivateKeySize() int
+
+	// CiphertextSize and SharedKeySize report the encapsulation sizes in bytes.
+	CiphertextSize() int
+	SharedKeySize() int
+
+	// GenerateKeyPair draws fresh randomness from crypto/rand.
+	GenerateKeyPair() (*PublicKey, *PrivateKey, error)
+
+	// DeriveKeyPair expands seed (via SHAKE) into the randomness Keygen
+	// consumes, so the same seed always yields the same key pair.
+	DeriveKeyPair(seed []byte) (*PublicKey, *PrivateKey, error)
+
+	// Encapsulate draws fresh randomness from crypto/rand.
+	Encapsulate(pk *PublicKey) (ct []byte, ss []byte, err error)
+
+	// EncapsulateDeterministically expands seed (via SHAKE) into the
+	// randomness Encapsulate consumes, so the same seed always yields the
+	// same ciphertext and shared secret.
+	EncapsulateDeterministically(pk *PublicKey, seed []byte) (ct []byte, ss []byte, err error)
+
+	// Decapsulate recovers the shared secret encapsulated in ct.
+	Decapsulate(sk *PrivateKey, ct []byte) ([]byte, error)
+
+	// UnmarshalPublicKey parses the raw wire encoding of a public key
+	// produced by this scheme's GenerateKeyPair/DeriveKeyPair.
+	UnmarshalPublicKey(data []byte) (*PublicKey, error)
+
+	// UnmarshalPrivateKey parses the raw wire encoding of a secret key
+	// produced by this scheme's GenerateKeyPair/DeriveKeyPair.
+	UnmarshalPrivateKey(data []byte) (*PrivateKey, error)
+}
+
+// PublicKey is the marshaled form of a FrodoKEM public key together with the
+// scheme that produced it, so it can be passed to Encapsulate without the
+// caller having to track which variant it belongs to.
+type PublicKey struct {
+	scheme *scheme
+	bytes  []byte
+}
+
+// PrivateKey is the marshaled form of a FrodoKEM secret key together with the
+// scheme that produced it.
+type PrivateKey struct {
+	scheme *scheme
+	bytes  []byte
+}
+
+// MarshalBinary returns the raw FrodoKEM wire encoding of pk, as produced by
+// FrodoKEM.Keygen.
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, len(pk.bytes))
+	copy(out, pk.bytes)
+	return out, nil
+}
+
+// UnmarshalBinary loads pk from the raw FrodoKEM wire encoding produced by
+// MarshalBinary. pk.scheme must already be set (see scheme.UnmarshalPublicKey).
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	if pk.scheme == nil {
+		return errors.New("frodokem: public key has no associated scheme")
+	}
+	if len(data) != pk.scheme.PublicKeySize() {
+		return errors.New("frodokem: incorrect public key length")
+	}
+	pk.bytes = append([]byte(nil), data...)
+	return nil
+}
+
+// MarshalText returns pk's wire encoding as lower-case hex.
+func (pk *PublicKey) MarshalText() ([]byte, error) {
+	raw, err := pk.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(raw)), nil
+}
+
+// UnmarshalText loads pk from the lower-case hex form produced by MarshalText.
+func (pk *PublicKey) UnmarshalText(text []byte) error {
+	raw, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	return pk.UnmarshalBinary(raw)
+}
+
+// Equal reports whether pk and other decode to the same bytes.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	if other == nil || len(pk.bytes) != len(other.bytes) {
+		return false
+	}
+	for i := range pk.bytes {
+		if pk.bytes[i] != other.bytes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary returns the raw FrodoKEM wire encoding of sk, as produced by
+// FrodoKEM.Keygen.
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	out := make([]byte, len(sk.bytes))
+	copy(out, sk.bytes)
+	return out, nil
+}
+
+// UnmarshalBinary loads sk from the raw FrodoKEM wire encoding produced by
+// MarshalBinary. sk.scheme must already be set (see scheme.UnmarshalPrivateKey).
+func (sk *PrivateKey) UnmarshalBinary(data []byte) error {
+	if sk.scheme == nil {
+		return errors.New("frodokem: private key has no associated scheme")
+	}
+	if len(data) != sk.scheme.PrivateKeySize() {
+		return errors.New("frodokem: incorrect private key length")
+	}
+	sk.bytes = append([]byte(nil), data...)
+	return nil
+}
+
+// MarshalText returns sk's wire encoding as lower-case hex.
+func (sk *PrivateKey) MarshalText() ([]byte, error) {
+	raw, err := sk.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(raw)), nil
+}
+
+// UnmarshalText loads sk from the lower-case hex form produced by MarshalText.
+func (sk *PrivateKey) UnmarshalText(text []byte) error {
+	raw, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	return sk.UnmarshalBinary(raw)
+}
+
+// scheme adapts a *FrodoKEM to the Scheme interface. Construction goes
+// through SchemeByName so every caller asking for "FrodoKEM-640-AES" shares
+// the same underlying kem instance.
+type scheme struct {
+	kem *FrodoKEM
+}
+
+var schemesMu sync.Mutex
+var schemes = map[string]*scheme{}
+
+// SchemeByName returns the Scheme for a FrodoKEM variant name, e.g.
+// "FrodoKEM-640-AES" or "FrodoKEM-976-SHAKE". It returns nil if name is not
+// a known variant.
+func SchemeByName(name string) Scheme {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+
+	if s, ok := schemes[name]; ok {
+		return s
+	}
+	k := newFrodoKEM(name)
+	if k == nil {
+		return nil
+	}
+	s := &scheme{kem: k}
+	schemes[name] = s
+	return s
+}
+
+// UnmarshalPublicKey parses the raw FrodoKEM wire encoding of a public key
+// produced by this scheme's GenerateKeyPair/DeriveKeyPair.
+func (s *scheme) UnmarshalPublicKey(data []byte) (*PublicKey, error) {
+	pk := &PublicKey{scheme: s}
+	if err := pk.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// UnmarshalPrivateKey parses the raw FrodoKEM wire encoding of a secret key
+// produced by this scheme's GenerateKeyPair/DeriveKeyPair.
+func (s *scheme) UnmarshalPrivateKey(data []byte) (*PrivateKey, error) {
+	sk := &PrivateKey{scheme: s}
+	if err := sk.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+func (s *scheme) Name() string        { return s.kem.Name() }
+func (s *scheme) PublicKeySize() int  { return s.kem.PublicKeyLen() }
+func (s *scheme) PrivateKeySize() int { return s.kem.SecretKeyLen() }
+func (s *scheme) CiphertextSize() int { return s.kem.CipherTextLen() }
+func (s *scheme) SharedKeySize() int  { return s.kem.SharedSecretLen() }
+
+func (s *scheme) GenerateKeyPair() (*PublicKey, *PrivateKey, error) {
+	pk, sk := s.kem.Keygen()
+	return &PublicKey{scheme: s, bytes: pk}, &PrivateKey{scheme: s, bytes: sk}, nil
+}
+
+// DeriveKeyPair makes Keygen deterministic by running it on a private
+// *FrodoKEM instance (not the shared s.kem one callers of Encapsulate/
+// Decapsulate may be using concurrently) whose RNG is a SHAKE128 keystream
+// seeded from seed. Overriding s.kem's RNG in place would race with every
+// other call going through this same cached scheme; a fresh instance per
+// call needs no lock because nothing else can see it.
+func (s *scheme) DeriveKeyPair(seed []byte) (*PublicKey, *PrivateKey, error) {
+	k := newFrodoKEM(s.kem.Name())
+	k.OverrideRng(shakeRng(seed))
+
+	pk, sk := k.Keygen()
+	return &PublicKey{scheme: s, bytes: pk}, &PrivateKey{scheme: s, bytes: sk}, nil
+}
+
+func (s *scheme) Encapsulate(pk *PublicKey) ([]byte, []byte, error) {
+	if pk.scheme != s {
+		return nil, nil, errors.New("frodokem: public key belongs to a different scheme")
+	}
+	return s.kem.Encapsulate(pk.bytes)
+}
+
+// EncapsulateDeterministically runs Encapsulate on a private *FrodoKEM
+// instance seeded the same way DeriveKeyPair is, for the same reason: s.kem
+// is shared across every caller of this cached scheme, so its RNG (and its
+// reused gemm.Workspace) can't be repurposed for one caller's seed without
+// racing everyone else's concurrent Encapsulate/Decapsulate calls.
+func (s *scheme) EncapsulateDeterministically(pk *PublicKey, seed []byte) ([]byte, []byte, error) {
+	if pk.scheme != s {
+		return nil, nil, errors.New("frodokem: public key belongs to a different scheme")
+	}
+	k := newFrodoKEM(s.kem.Name())
+	k.OverrideRng(shakeRng(seed))
+
+	return k.Encapsulate(pk.bytes)
+}
+
+func (s *scheme) Decapsulate(sk *PrivateKey, ct []byte) ([]byte, error) {
+	if sk.scheme != s {
+		return nil, errors.New("frodokem: private key belongs to a different scheme")
+	}
+	return s.kem.Dencapsulate(sk.bytes, ct)
+}
+
+// shakeRng expands seed into an arbitrary-length keystream via SHAKE128, so
+// DeriveKeyPair/EncapsulateDeterministically can feed FrodoKEM's rng hook
+// (which expects fresh bytes on every call) from a single fixed seed.
+func shakeRng(seed []byte) func([]byte) {
+	reader := sha3.NewShake128()
+	reader.Write(seed)
+	return func(out []byte) {
+		reader.Read(out)
+	}
+}
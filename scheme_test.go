@@ -0,0 +1,167 @@
+package go_frodokem
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSchemeByNameLookup checks that SchemeByName resolves a known variant
+// name and rejects an unknown one.
+func TestSchemeByNameLookup(t *testing.T) {
+	s := SchemeByName("FrodoKEM-640-AES")
+	if s == nil {
+		t.Fatal("SchemeByName(\"FrodoKEM-640-AES\") returned nil")
+	}
+	if got := s.Name(); got != "FrodoKEM-640-AES" {
+		t.Fatalf("Name() = %q, want %q", got, "FrodoKEM-640-AES")
+	}
+
+	if s := SchemeByName("not-a-real-variant"); s != nil {
+		t.Fatalf("SchemeByName(\"not-a-real-variant\") = %v, want nil", s)
+	}
+}
+
+// TestPublicKeyMarshalRoundTrip checks that a PublicKey survives a
+// MarshalBinary/UnmarshalPublicKey round trip, which is the whole point of
+// exposing those methods on Scheme: a caller storing a key to disk or the
+// wire must get back something Equal to what it marshaled.
+func TestPublicKeyMarshalRoundTrip(t *testing.T) {
+	s := SchemeByName("FrodoKEM-640-AES")
+	pk, _, err := s.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair: %v", err)
+	}
+
+	raw, err := pk.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	pk2, err := s.UnmarshalPublicKey(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalPublicKey: %v", err)
+	}
+	if !pk.Equal(pk2) {
+		t.Fatal("public key does not survive MarshalBinary/UnmarshalPublicKey round trip")
+	}
+
+	text, err := pk.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	// UnmarshalText requires pk3.scheme to already be set (see
+	// UnmarshalBinary), same as UnmarshalPublicKey's pattern above.
+	pk3 := &PublicKey{scheme: s.(*scheme)}
+	if err := pk3.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if !pk.Equal(pk3) {
+		t.Fatal("public key does not survive MarshalText/UnmarshalText round trip")
+	}
+}
+
+// TestPrivateKeyMarshalRoundTrip is PublicKey's round-trip test for the
+// secret-key side, which has no Equal method, so bytes.Equal on the
+// marshaled form stands in for it.
+func TestPrivateKeyMarshalRoundTrip(t *testing.T) {
+	s := SchemeByName("FrodoKEM-640-AES")
+	_, sk, err := s.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair: %v", err)
+	}
+
+	raw, err := sk.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	sk2, err := s.UnmarshalPrivateKey(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey: %v", err)
+	}
+	raw2, err := sk2.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if !bytes.Equal(raw, raw2) {
+		t.Fatal("private key does not survive MarshalBinary/UnmarshalPrivateKey round trip")
+	}
+}
+
+// TestDeriveKeyPairDeterministic checks that DeriveKeyPair is a pure
+// function of its seed: the same seed must yield the same key pair every
+// time, and different seeds must yield different ones.
+func TestDeriveKeyPairDeterministic(t *testing.T) {
+	s := SchemeByName("FrodoKEM-640-AES")
+
+	seedA := bytes.Repeat([]byte{0x42}, 32)
+	seedB := bytes.Repeat([]byte{0x99}, 32)
+
+	pk1, sk1, err := s.DeriveKeyPair(seedA)
+	if err != nil {
+		t.Fatalf("DeriveKeyPair: %v", err)
+	}
+	pk2, sk2, err := s.DeriveKeyPair(seedA)
+	if err != nil {
+		t.Fatalf("DeriveKeyPair: %v", err)
+	}
+	if !pk1.Equal(pk2) {
+		t.Fatal("DeriveKeyPair(seed) produced different public keys for the same seed")
+	}
+	raw1, _ := sk1.MarshalBinary()
+	raw2, _ := sk2.MarshalBinary()
+	if !bytes.Equal(raw1, raw2) {
+		t.Fatal("DeriveKeyPair(seed) produced different secret keys for the same seed")
+	}
+
+	pk3, _, err := s.DeriveKeyPair(seedB)
+	if err != nil {
+		t.Fatalf("DeriveKeyPair: %v", err)
+	}
+	if pk1.Equal(pk3) {
+		t.Fatal("DeriveKeyPair produced the same public key for two different seeds")
+	}
+}
+
+// TestEncapsulateDeterministically checks EncapsulateDeterministically the
+// same way: same seed -> same (ct, ss); different seed -> different ct.
+// It also checks the derivation is actually usable, i.e. Decapsulate
+// recovers the same shared secret Encapsulate produced.
+func TestEncapsulateDeterministically(t *testing.T) {
+	s := SchemeByName("FrodoKEM-640-AES")
+	pk, sk, err := s.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair: %v", err)
+	}
+
+	seedA := bytes.Repeat([]byte{0x11}, 32)
+	seedB := bytes.Repeat([]byte{0x22}, 32)
+
+	ct1, ss1, err := s.EncapsulateDeterministically(pk, seedA)
+	if err != nil {
+		t.Fatalf("EncapsulateDeterministically: %v", err)
+	}
+	ct2, ss2, err := s.EncapsulateDeterministically(pk, seedA)
+	if err != nil {
+		t.Fatalf("EncapsulateDeterministically: %v", err)
+	}
+	if !bytes.Equal(ct1, ct2) || !bytes.Equal(ss1, ss2) {
+		t.Fatal("EncapsulateDeterministically(pk, seed) differed between two calls with the same seed")
+	}
+
+	ct3, _, err := s.EncapsulateDeterministically(pk, seedB)
+	if err != nil {
+		t.Fatalf("EncapsulateDeterministically: %v", err)
+	}
+	if bytes.Equal(ct1, ct3) {
+		t.Fatal("EncapsulateDeterministically produced the same ciphertext for two different seeds")
+	}
+
+	ssDec, err := s.Decapsulate(sk, ct1)
+	if err != nil {
+		t.Fatalf("Decapsulate: %v", err)
+	}
+	if !bytes.Equal(ss1, ssDec) {
+		t.Fatal("Decapsulate did not recover the shared secret EncapsulateDeterministically produced")
+	}
+}
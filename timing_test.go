@@ -0,0 +1,73 @@
+package go_frodokem
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// timingSamples runs decapsulate on ct n times and returns the elapsed
+// durations in call order.
+func timingSamples(k *FrodoKEM, sk []uint8, ct []uint8, n int) []time.Duration {
+	d := make([]time.Duration, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		_, _ = k.Dencapsulate(sk, ct)
+		d[i] = time.Since(start)
+	}
+	return d
+}
+
+// medianDuration returns the median of d. d is sorted in place.
+func medianDuration(d []time.Duration) time.Duration {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	return d[len(d)/2]
+}
+
+// TestDecapsulateConstantTime is a lightweight, dudect-inspired check: it
+// compares the median decapsulation time of one fixed ciphertext, decapsulated
+// repeatedly, against many independently random ciphertexts. sample/decode used to
+// branch on secret-derived data (see the implicit-rejection path in
+// Dencapsulate), which showed up as a large, repeatable gap between the two
+// medians; a constant-time implementation keeps the gap within noise.
+//
+// This is not a substitute for a proper dudect run (which needs thousands of
+// interleaved samples and a t-test) but it catches gross regressions cheaply
+// in CI.
+func TestDecapsulateConstantTime(t *testing.T) {
+	k := NewFrodoKEM640AES()
+	pk, sk := k.Keygen()
+
+	fixedCt, _, err := k.Encapsulate(pk)
+	if err != nil {
+		t.Fatalf("Encapsulate: %v", err)
+	}
+
+	const samples = 200
+	fixedTimes := timingSamples(k, sk, fixedCt, samples)
+
+	randomTimes := make([]time.Duration, samples)
+	for i := 0; i < samples; i++ {
+		ct, _, err := k.Encapsulate(pk)
+		if err != nil {
+			t.Fatalf("Encapsulate: %v", err)
+		}
+		randomTimes[i] = timingSamples(k, sk, ct, 1)[0]
+	}
+
+	fixedMedian := medianDuration(fixedTimes)
+	randomMedian := medianDuration(randomTimes)
+
+	diff := fixedMedian - randomMedian
+	if diff < 0 {
+		diff = -diff
+	}
+
+	// Allow a generous margin: this test runs on shared CI hardware and is
+	// only meant to flag a branch that takes a clearly different code path,
+	// not to certify true constant-timeness.
+	threshold := randomMedian / 2
+	if diff > threshold {
+		t.Fatalf("decapsulation timing differs by %v between fixed and random ciphertexts (fixed median %v, random median %v) — possible secret-dependent branch", diff, fixedMedian, randomMedian)
+	}
+}
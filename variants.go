@@ -0,0 +1,38 @@
+package go_frodokem
+
+//go:generate go run gen.go
+
+// newFrodoKEM constructs a fresh *FrodoKEM for one of the standard variant
+// names. It is the single place that maps a human-readable name to the
+// corresponding parameter-table constructor, so SchemeByName stays in sync
+// as new variants are added.
+func newFrodoKEM(name string) *FrodoKEM {
+	switch name {
+	case "FrodoKEM-640-AES":
+		return NewFrodoKEM640AES()
+	case "FrodoKEM-640-SHAKE":
+		return NewFrodoKEM640SHAKE()
+	case "FrodoKEM-976-AES":
+		return NewFrodoKEM976AES()
+	case "FrodoKEM-976-SHAKE":
+		return NewFrodoKEM976SHAKE()
+	case "FrodoKEM-1344-AES":
+		return NewFrodoKEM1344AES()
+	case "FrodoKEM-1344-SHAKE":
+		return NewFrodoKEM1344SHAKE()
+	default:
+		return nil
+	}
+}
+
+// SchemeNames lists every variant name accepted by SchemeByName.
+func SchemeNames() []string {
+	return []string{
+		"FrodoKEM-640-AES",
+		"FrodoKEM-640-SHAKE",
+		"FrodoKEM-976-AES",
+		"FrodoKEM-976-SHAKE",
+		"FrodoKEM-1344-AES",
+		"FrodoKEM-1344-SHAKE",
+	}
+}
@@ -0,0 +1,23 @@
+package go_frodokem
+
+import "testing"
+
+// TestSchemeNamesMatchNewFrodoKEM checks that every name SchemeNames lists
+// is one newFrodoKEM actually knows how to construct, and that an unknown
+// name is rejected rather than silently returning some default variant.
+func TestSchemeNamesMatchNewFrodoKEM(t *testing.T) {
+	for _, name := range SchemeNames() {
+		k := newFrodoKEM(name)
+		if k == nil {
+			t.Errorf("newFrodoKEM(%q) = nil, want a *FrodoKEM", name)
+			continue
+		}
+		if got := k.Name(); got != name {
+			t.Errorf("newFrodoKEM(%q).Name() = %q, want %q", name, got, name)
+		}
+	}
+
+	if k := newFrodoKEM("not-a-real-variant"); k != nil {
+		t.Errorf("newFrodoKEM(\"not-a-real-variant\") = %v, want nil", k)
+	}
+}